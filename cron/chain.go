@@ -0,0 +1,89 @@
+package cron
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GuoCeng/time-wheel/logging"
+)
+
+// JobWrapper decorates the given Job with some behavior.
+type JobWrapper func(Job) Job
+
+// Chain is a sequence of JobWrappers that decorates submitted jobs with
+// behaviors such as panic recovery or overlap handling.
+type Chain struct {
+	wrappers []JobWrapper
+}
+
+// NewChain returns a Chain consisting of the given JobWrappers.
+func NewChain(c ...JobWrapper) Chain {
+	return Chain{c}
+}
+
+// Then decorates the given job with all JobWrappers in the Chain, in the
+// order in which they were added. Chain(f1, f2, f3).Then(job) == f1(f2(f3(job))).
+func (c Chain) Then(j Job) Job {
+	for i := range c.wrappers {
+		j = c.wrappers[len(c.wrappers)-i-1](j)
+	}
+	return j
+}
+
+// Recover panics in wrapped jobs and logs them with the provided logger.
+func Recover(logger logging.Logger) JobWrapper {
+	return func(j Job) Job {
+		return FuncJob(func() {
+			defer func() {
+				if r := recover(); r != nil {
+					const size = 64 << 10
+					buf := make([]byte, size)
+					buf = buf[:runtime.Stack(buf, false)]
+					err, ok := r.(error)
+					if !ok {
+						err = fmt.Errorf("%v", r)
+					}
+					logger.Error(err, "panic", "stack", "...\n"+string(buf))
+				}
+			}()
+			j.Run()
+		})
+	}
+}
+
+// DelayIfStillRunning serializes jobs, delaying subsequent runs until the
+// previous one has completed. Jobs running after a delay of more than a
+// minute have the delay logged at Info.
+func DelayIfStillRunning(logger logging.Logger) JobWrapper {
+	return func(j Job) Job {
+		var mu sync.Mutex
+		return FuncJob(func() {
+			start := time.Now()
+			mu.Lock()
+			defer mu.Unlock()
+			if dur := time.Since(start); dur > time.Minute {
+				logger.Info("delay", "duration", dur)
+			}
+			j.Run()
+		})
+	}
+}
+
+// SkipIfStillRunning skips an invocation of the Job if a previous invocation
+// is still running. It logs skips to the given logger at Info level.
+func SkipIfStillRunning(logger logging.Logger) JobWrapper {
+	return func(j Job) Job {
+		var running int32
+		return FuncJob(func() {
+			if !atomic.CompareAndSwapInt32(&running, 0, 1) {
+				logger.Info("skip")
+				return
+			}
+			defer atomic.StoreInt32(&running, 0)
+			j.Run()
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package cron
+
+import "time"
+
+// Clock abstracts the passage of time used to drive the scheduler, so tests
+// can advance virtual time deterministically instead of waiting on real
+// sleeps and timers. The default Clock (realClock) is backed by the time
+// package; MockClock is provided for tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a Timer that fires once d has elapsed.
+	NewTimer(d time.Duration) Timer
+	// Sleep blocks for d.
+	Sleep(d time.Duration)
+}
+
+// Timer is the subset of *time.Timer this package relies on, abstracted so
+// MockClock can drive it without a real timer running in the background.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// realClock is the default Clock, backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                { return time.Now() }
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+func (realClock) Sleep(d time.Duration)          { time.Sleep(d) }
+
+type realTimer struct{ t *time.Timer }
+
+func (r realTimer) C() <-chan time.Time { return r.t.C }
+func (r realTimer) Stop() bool          { return r.t.Stop() }
@@ -0,0 +1,38 @@
+package cron
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// A job scheduled every second should not run until the mock clock is
+// advanced, and should run once it is - all without any real waiting.
+func TestMockClockDrivesSchedule(t *testing.T) {
+	clock := NewMockClock()
+	cron := New(WithChain(), WithClock(clock))
+	cron.Start()
+	defer cron.Stop()
+
+	// AddFunc returns once the run loop has received the new entry, before
+	// it has set a timer for it. Entries round-trips through the run loop,
+	// which only answers once it is waiting on that timer, so the clock.Add
+	// below cannot race ahead of the timer being registered.
+	var calls int64
+	cron.AddFunc("* * * * * *", func() { atomic.AddInt64(&calls, 1) })
+	cron.Entries()
+
+	if n := atomic.LoadInt64(&calls); n != 0 {
+		t.Fatalf("expected no runs before advancing the clock, got %d", n)
+	}
+
+	clock.Add(2 * time.Second)
+
+	deadline := time.Now().Add(OneSecond)
+	for atomic.LoadInt64(&calls) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := atomic.LoadInt64(&calls); n == 0 {
+		t.Fatal("expected job to run after advancing the mock clock")
+	}
+}
@@ -0,0 +1,467 @@
+// Package cron implements a cron spec scheduler, similar in spirit to
+// robfig/cron: entries are kept sorted by next run time, and the run loop
+// sleeps via a Clock-provided Timer until the soonest one fires (or a
+// channel operation - adding/removing/pausing an entry, a stop request -
+// wakes it early).
+package cron
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GuoCeng/time-wheel/logging"
+)
+
+// Cron keeps track of any number of entries, invoking the associated func as
+// specified by the schedule. It may be started, stopped, and the entries
+// may be inspected while running.
+type Cron struct {
+	entries   []*Entry
+	chain     Chain
+	stop      chan struct{}
+	add       chan *Entry
+	remove    chan EntryID
+	pause     chan EntryID
+	resume    chan EntryID
+	runNow    chan runNowRequest
+	snapshot  chan chan []Entry
+	running   bool
+	logger    logging.Logger
+	runningMu sync.Mutex
+	location  *time.Location
+	parser    Parser
+	nextID    EntryID
+	jobWaiter sync.WaitGroup
+	clock     Clock
+}
+
+// Job is the interface submitted schedules must implement.
+type Job interface {
+	Run()
+}
+
+// Schedule describes a job's duty cycle.
+type Schedule interface {
+	// Next returns the next activation time, later than the given time.
+	// Next is invoked initially, and then each time the job is run.
+	Next(time.Time) time.Time
+}
+
+// EntryID identifies an entry within a Cron instance.
+type EntryID int
+
+// Entry consists of a schedule and the func to execute on that schedule.
+type Entry struct {
+	// ID is the cron-assigned ID of this entry, which may be used to look
+	// up a snapshot or remove it.
+	ID EntryID
+
+	// Schedule on which this job should be run.
+	Schedule Schedule
+
+	// Next time the job will run, or the zero time if Cron has not been
+	// started or this entry's schedule is unsatisfiable.
+	Next time.Time
+
+	// Prev is the last time this job was run, or the zero time if never.
+	Prev time.Time
+
+	// WrappedJob is the thing to run when the Schedule is activated.
+	WrappedJob Job
+
+	// Job is the thing that was submitted to cron.
+	Job Job
+
+	// paused tracks whether this entry is currently suppressed: its
+	// schedule still advances, but WrappedJob.Run is skipped.
+	paused *pausedFlag
+}
+
+// Valid returns true if this is not the zero entry.
+func (e Entry) Valid() bool { return e.ID != 0 }
+
+// Paused reports whether this entry is currently paused.
+func (e Entry) Paused() bool {
+	if e.paused == nil {
+		return false
+	}
+	return e.paused.get()
+}
+
+// byTime sorts a slice of Entries in ascending order of Next time, with the
+// zero time (unscheduled entries) sorted last.
+type byTime []*Entry
+
+func (s byTime) Len() int      { return len(s) }
+func (s byTime) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byTime) Less(i, j int) bool {
+	if s[i].Next.IsZero() {
+		return false
+	}
+	if s[j].Next.IsZero() {
+		return true
+	}
+	return s[i].Next.Before(s[j].Next)
+}
+
+// New returns a new Cron job runner, modified by the given options.
+//
+// Available Settings:
+//
+//	Location
+//	  The timezone of the times the scheduler passes to Schedule.Next.
+//	  Specs parsed by ParseStandard are still evaluated in time.Local,
+//	  since SpecSchedule.Next converts to its own Location.
+//
+//	Parser
+//	  Parser converts cron spec strings into cron.Schedules.
+//
+//	Chain
+//	  Wrap submitted jobs with cron.JobWrappers.
+func New(opts ...Option) *Cron {
+	c := &Cron{
+		entries:  nil,
+		add:      make(chan *Entry),
+		stop:     make(chan struct{}),
+		remove:   make(chan EntryID),
+		pause:    make(chan EntryID),
+		resume:   make(chan EntryID),
+		runNow:   make(chan runNowRequest),
+		snapshot: make(chan chan []Entry),
+		running:  false,
+		logger:   logging.DefaultLogger,
+		location: time.Local,
+		parser:   standardParser{},
+		clock:    realClock{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// FuncJob is a wrapper that turns a func() into a cron.Job.
+type FuncJob func()
+
+func (f FuncJob) Run() { f() }
+
+// AddFunc adds a func to the Cron to be run on the given schedule. The spec
+// is parsed using this Cron instance's Parser.
+func (c *Cron) AddFunc(spec string, cmd func(), opts ...EntryOption) (EntryID, error) {
+	return c.AddJob(spec, FuncJob(cmd), opts...)
+}
+
+// AddJob adds a Job to the Cron to be run on the given schedule. The spec
+// is parsed using this Cron instance's Parser.
+func (c *Cron) AddJob(spec string, cmd Job, opts ...EntryOption) (EntryID, error) {
+	schedule, err := c.parser.Parse(spec)
+	if err != nil {
+		return 0, err
+	}
+	return c.Schedule(schedule, cmd, opts...), nil
+}
+
+// Schedule adds a Job to the Cron to be run on the given schedule. The job
+// is wrapped with the configured Chain.
+func (c *Cron) Schedule(schedule Schedule, cmd Job, opts ...EntryOption) EntryID {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+	c.nextID++
+	entry := &Entry{
+		ID:         c.nextID,
+		Schedule:   schedule,
+		WrappedJob: c.chain.Then(cmd),
+		Job:        cmd,
+		paused:     newPausedFlag(false),
+	}
+	for _, opt := range opts {
+		opt(entry)
+	}
+	if !c.running {
+		c.entries = append(c.entries, entry)
+	} else {
+		c.add <- entry
+	}
+	return entry.ID
+}
+
+// Entries returns a snapshot of the cron entries.
+func (c *Cron) Entries() []Entry {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+	if c.running {
+		replyChan := make(chan []Entry, 1)
+		c.snapshot <- replyChan
+		return <-replyChan
+	}
+	return c.entrySnapshot()
+}
+
+// Location gets the time zone location.
+func (c *Cron) Location() *time.Location {
+	return c.location
+}
+
+// Entry returns a snapshot of the given entry, or the zero Entry (for which
+// Valid reports false) if it couldn't be found.
+func (c *Cron) Entry(id EntryID) Entry {
+	for _, entry := range c.Entries() {
+		if id == entry.ID {
+			return entry
+		}
+	}
+	return Entry{}
+}
+
+// Remove an entry from being run in the future.
+func (c *Cron) Remove(id EntryID) {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+	if c.running {
+		c.remove <- id
+	} else {
+		c.removeEntry(id)
+	}
+}
+
+// Pause suppresses the entry with the given id: its schedule keeps
+// advancing, but its job stops running until Resume is called.
+func (c *Cron) Pause(id EntryID) {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+	if c.running {
+		c.pause <- id
+	} else {
+		c.setPaused(id, true)
+	}
+}
+
+// Resume re-enables an entry previously suppressed with Pause.
+func (c *Cron) Resume(id EntryID) {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+	if c.running {
+		c.resume <- id
+	} else {
+		c.setPaused(id, false)
+	}
+}
+
+// setPaused updates the paused flag of the entry with the given id, if it
+// exists.
+func (c *Cron) setPaused(id EntryID, paused bool) {
+	for _, e := range c.entries {
+		if e.ID == id {
+			e.paused.set(paused)
+			return
+		}
+	}
+}
+
+// runNowRequest asks the run loop to trigger an entry immediately, and
+// reports back whether an entry with that ID was found.
+type runNowRequest struct {
+	id    EntryID
+	reply chan error
+}
+
+// RunNow immediately runs the entry with the given ID through the same
+// wrapped job (and thus the same JobWrapper chain) used by its regular
+// schedule, without disturbing that schedule's Next/Prev. It returns an
+// error if no entry with that ID exists.
+func (c *Cron) RunNow(id EntryID) error {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+
+	if !c.running {
+		return c.triggerEntry(id)
+	}
+
+	reply := make(chan error, 1)
+	c.runNow <- runNowRequest{id: id, reply: reply}
+	return <-reply
+}
+
+// triggerEntry looks up the entry with the given id and, if found, starts
+// its wrapped job. It must only be called from the run loop goroutine, or
+// while Cron is not running.
+func (c *Cron) triggerEntry(id EntryID) error {
+	for _, e := range c.entries {
+		if e.ID == id {
+			c.startJob(e.WrappedJob)
+			return nil
+		}
+	}
+	return fmt.Errorf("cron: no entry with id %d", id)
+}
+
+// Start the cron scheduler in its own goroutine, or no-op if already
+// started.
+func (c *Cron) Start() {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+	if c.running {
+		return
+	}
+	c.running = true
+	go c.run()
+}
+
+// Run the cron scheduler, or no-op if already running.
+func (c *Cron) Run() {
+	c.runningMu.Lock()
+	if c.running {
+		c.runningMu.Unlock()
+		return
+	}
+	c.running = true
+	c.runningMu.Unlock()
+	c.run()
+}
+
+// run the scheduler. this is private just due to the need to synchronize
+// access to the 'running' state variable.
+func (c *Cron) run() {
+	now := c.now()
+	for _, entry := range c.entries {
+		entry.Next = entry.Schedule.Next(now)
+	}
+
+	for {
+		sort.Sort(byTime(c.entries))
+
+		var timer Timer
+		if len(c.entries) == 0 || c.entries[0].Next.IsZero() {
+			// If there are no entries yet, just sleep - it still handles
+			// new entries and stop requests.
+			timer = c.clock.NewTimer(100000 * time.Hour)
+		} else {
+			timer = c.clock.NewTimer(c.entries[0].Next.Sub(now))
+		}
+
+		for {
+			select {
+			case now = <-timer.C():
+				now = now.In(c.location)
+				// Run every entry whose next time was less than now.
+				for _, e := range c.entries {
+					if e.Next.After(now) || e.Next.IsZero() {
+						break
+					}
+					if !e.paused.get() {
+						c.startJob(e.WrappedJob)
+					}
+					e.Prev = e.Next
+					e.Next = e.Schedule.Next(now)
+				}
+
+			case newEntry := <-c.add:
+				timer.Stop()
+				now = c.now()
+				newEntry.Next = newEntry.Schedule.Next(now)
+				c.entries = append(c.entries, newEntry)
+
+			case replyChan := <-c.snapshot:
+				replyChan <- c.entrySnapshot()
+				continue
+
+			case req := <-c.runNow:
+				req.reply <- c.triggerEntry(req.id)
+				continue
+
+			case id := <-c.remove:
+				timer.Stop()
+				now = c.now()
+				c.removeEntry(id)
+
+			case id := <-c.pause:
+				timer.Stop()
+				now = c.now()
+				c.setPaused(id, true)
+
+			case id := <-c.resume:
+				timer.Stop()
+				now = c.now()
+				c.setPaused(id, false)
+
+			case <-c.stop:
+				timer.Stop()
+				return
+			}
+
+			break
+		}
+	}
+}
+
+// startJob runs the given job in a new goroutine, tracked by the job
+// waiter so the context returned by Stop (and StopGracefully) can wait for
+// in-flight jobs.
+func (c *Cron) startJob(j Job) {
+	c.jobWaiter.Add(1)
+	go func() {
+		defer c.jobWaiter.Done()
+		j.Run()
+	}()
+}
+
+// now returns the current time in the location set on this Cron instance.
+func (c *Cron) now() time.Time {
+	return c.clock.Now().In(c.location)
+}
+
+// Stop stops the cron scheduler if it is running; otherwise it does
+// nothing. A context is returned so the caller can wait for running jobs,
+// if any, to complete.
+func (c *Cron) Stop() context.Context {
+	c.runningMu.Lock()
+	defer c.runningMu.Unlock()
+	if c.running {
+		c.stop <- struct{}{}
+		c.running = false
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		c.jobWaiter.Wait()
+		cancel()
+	}()
+	return ctx
+}
+
+// StopGracefully stops the cron scheduler, then blocks until every job that
+// was already running has finished, or ctx is cancelled first. It is a
+// synchronous convenience wrapper around Stop, for callers that want to
+// wait inline (e.g. during a server's graceful shutdown) rather than hold
+// onto the returned context themselves.
+func (c *Cron) StopGracefully(ctx context.Context) error {
+	done := c.Stop()
+	select {
+	case <-done.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// entrySnapshot returns a copy of the current cron entry list.
+func (c *Cron) entrySnapshot() []Entry {
+	var entries = make([]Entry, len(c.entries))
+	for i, e := range c.entries {
+		entries[i] = *e
+	}
+	return entries
+}
+
+// removeEntry removes the entry with the given id from the entries slice.
+func (c *Cron) removeEntry(id EntryID) {
+	var entries []*Entry
+	for _, e := range c.entries {
+		if e.ID != id {
+			entries = append(entries, e)
+		}
+	}
+	c.entries = entries
+}
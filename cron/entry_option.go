@@ -0,0 +1,39 @@
+package cron
+
+import "sync"
+
+// EntryOption configures an Entry at the time it is added to a Cron.
+type EntryOption func(*Entry)
+
+// WithPaused adds the entry in the given paused state. A paused entry still
+// advances its schedule on every tick, but its job is not run until it is
+// resumed via Cron.Resume.
+func WithPaused(paused bool) EntryOption {
+	return func(e *Entry) {
+		e.paused.set(paused)
+	}
+}
+
+// pausedFlag guards an Entry's paused state behind a mutex. It is stored as
+// a pointer on Entry (rather than embedding the mutex directly) so that
+// Entry can keep being copied freely by Entries()/entrySnapshot.
+type pausedFlag struct {
+	mu    sync.Mutex
+	value bool
+}
+
+func newPausedFlag(paused bool) *pausedFlag {
+	return &pausedFlag{value: paused}
+}
+
+func (p *pausedFlag) get() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.value
+}
+
+func (p *pausedFlag) set(paused bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.value = paused
+}
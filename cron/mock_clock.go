@@ -0,0 +1,93 @@
+package cron
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MockClock is a Clock that only advances when Add or Set is called,
+// letting tests drive the scheduler deterministically instead of waiting
+// on real time.
+type MockClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*mockTimer
+}
+
+// NewMockClock returns a MockClock initialized to the current wall-clock
+// time.
+func NewMockClock() *MockClock {
+	return &MockClock{now: time.Now()}
+}
+
+// Now returns the clock's current virtual time.
+func (m *MockClock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// NewTimer registers a Timer that fires once the clock has been advanced by
+// at least d.
+func (m *MockClock) NewTimer(d time.Duration) Timer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := &mockTimer{clock: m, deadline: m.now.Add(d), c: make(chan time.Time, 1)}
+	m.timers = append(m.timers, t)
+	return t
+}
+
+// Sleep advances the clock by d, firing any timers that elapse as a
+// result, and returns immediately - there's no real time to wait out.
+func (m *MockClock) Sleep(d time.Duration) {
+	m.Add(d)
+}
+
+// Add advances the clock by d.
+func (m *MockClock) Add(d time.Duration) {
+	m.Set(m.Now().Add(d))
+}
+
+// Set moves the clock to t, firing (and unregistering) any timer whose
+// deadline has now elapsed, in deadline order.
+func (m *MockClock) Set(t time.Time) {
+	m.mu.Lock()
+	m.now = t
+	var fired []*mockTimer
+	remaining := m.timers[:0]
+	for _, tm := range m.timers {
+		if !tm.deadline.After(t) {
+			fired = append(fired, tm)
+		} else {
+			remaining = append(remaining, tm)
+		}
+	}
+	m.timers = remaining
+	m.mu.Unlock()
+
+	sort.Slice(fired, func(i, j int) bool { return fired[i].deadline.Before(fired[j].deadline) })
+	for _, tm := range fired {
+		tm.c <- t
+	}
+}
+
+type mockTimer struct {
+	clock    *MockClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+func (t *mockTimer) C() <-chan time.Time { return t.c }
+
+func (t *mockTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, tm := range t.clock.timers {
+		if tm == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
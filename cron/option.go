@@ -0,0 +1,48 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/GuoCeng/time-wheel/logging"
+)
+
+// Option represents a modification to the default behavior of a Cron.
+type Option func(*Cron)
+
+// WithLocation overrides the timezone of the cron instance.
+func WithLocation(loc *time.Location) Option {
+	return func(c *Cron) {
+		c.location = loc
+	}
+}
+
+// WithParser overrides the parser used to parse cron specs.
+func WithParser(p Parser) Option {
+	return func(c *Cron) {
+		c.parser = p
+	}
+}
+
+// WithChain wraps every job reaching this Cron with the given set of
+// modifications.
+func WithChain(wrappers ...JobWrapper) Option {
+	return func(c *Cron) {
+		c.chain = NewChain(wrappers...)
+	}
+}
+
+// WithLogger uses the provided logger for internal logging.
+func WithLogger(logger logging.Logger) Option {
+	return func(c *Cron) {
+		c.logger = logger
+	}
+}
+
+// WithClock overrides the Clock used to tell time and schedule timers,
+// which is realClock (wall-clock time) by default. Tests can inject a
+// MockClock to drive scheduling deterministically.
+func WithClock(clock Clock) Option {
+	return func(c *Cron) {
+		c.clock = clock
+	}
+}
@@ -0,0 +1,193 @@
+package cron
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parser converts a cron spec string into a Schedule.
+type Parser interface {
+	Parse(spec string) (Schedule, error)
+}
+
+// standardParser is the default Parser, implementing ParseStandard.
+type standardParser struct{}
+
+func (standardParser) Parse(spec string) (Schedule, error) {
+	return ParseStandard(spec)
+}
+
+// ParseStandard parses a cron spec in the six-field form this package uses
+// throughout: "Seconds Minutes Hours DayOfMonth Month DayOfWeek". Each
+// field may be '*' or '?' (treated as equivalent, quartz-style), a single
+// value, a range ("1-5"), a list ("1,3,5"), or a step ("*/15", "1-30/5").
+// In day-of-month and day-of-week, '?' reads as "no specific value"; it
+// parses exactly like '*', which is the behavior dayMatches relies on.
+func ParseStandard(spec string) (Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("expected exactly 6 fields, found %d: %q", len(fields), spec)
+	}
+
+	var err error
+	field := func(field string, r bounds) uint64 {
+		if err != nil {
+			return 0
+		}
+		var bits uint64
+		bits, err = parseField(field, r)
+		return bits
+	}
+
+	second := field(fields[0], seconds)
+	minute := field(fields[1], minutes)
+	hour := field(fields[2], hours)
+	dayofmonth := field(fields[3], dom)
+	month := field(fields[4], months)
+	dayofweek := field(fields[5], dow)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SpecSchedule{
+		Second:   second,
+		Minute:   minute,
+		Hour:     hour,
+		Dom:      dayofmonth,
+		Month:    month,
+		Dow:      dayofweek,
+		Location: time.Local,
+	}, nil
+}
+
+// parseField parses a single cron field into its bit set representation.
+func parseField(field string, r bounds) (uint64, error) {
+	var bits uint64
+
+	if field == "*" || field == "?" {
+		bits = ^uint64(0) | starBit
+		return maskOverflow(bits, r), nil
+	}
+
+	for _, expr := range strings.Split(field, ",") {
+		bit, err := parseRange(expr, r)
+		if err != nil {
+			return 0, err
+		}
+		bits |= bit
+	}
+	return bits, nil
+}
+
+// parseRange parses a range such as "1-5/2", "*/15", or a single value,
+// returning the bits it sets. The 'star' marker bit is included only for a
+// bare '*' or '?' without a step.
+func parseRange(expr string, r bounds) (uint64, error) {
+	var (
+		start, end, step uint
+		rangeAndStep     = strings.Split(expr, "/")
+		lowAndHigh       = strings.Split(rangeAndStep[0], "-")
+		singleDigit      = len(lowAndHigh) == 1
+	)
+
+	var extra uint64
+	if lowAndHigh[0] == "*" || lowAndHigh[0] == "?" {
+		start = r.min
+		end = r.max
+		extra = starBit
+	} else {
+		var err error
+		start, err = parseIntOrName(lowAndHigh[0], r.names)
+		if err != nil {
+			return 0, err
+		}
+		switch len(lowAndHigh) {
+		case 1:
+			end = start
+		case 2:
+			end, err = parseIntOrName(lowAndHigh[1], r.names)
+			if err != nil {
+				return 0, err
+			}
+		default:
+			return 0, fmt.Errorf("too many hyphens: %q", expr)
+		}
+	}
+
+	switch len(rangeAndStep) {
+	case 1:
+		step = 1
+	case 2:
+		var err error
+		step, err = mustParseInt(rangeAndStep[1])
+		if err != nil {
+			return 0, err
+		}
+		// Special handling: "N/step" means "N-max/step".
+		if singleDigit {
+			end = r.max
+		}
+		extra = 0
+	default:
+		return 0, fmt.Errorf("too many slashes: %q", expr)
+	}
+
+	if start < r.min {
+		return 0, fmt.Errorf("beginning of range (%d) below minimum (%d): %q", start, r.min, expr)
+	}
+	if end > r.max {
+		return 0, fmt.Errorf("end of range (%d) above maximum (%d): %q", end, r.max, expr)
+	}
+	if start > end {
+		return 0, fmt.Errorf("beginning of range (%d) beyond end of range (%d): %q", start, end, expr)
+	}
+	if step == 0 {
+		return 0, fmt.Errorf("step of range should be a positive number: %q", expr)
+	}
+
+	return getBits(start, end, step) | extra, nil
+}
+
+func parseIntOrName(expr string, names map[string]uint) (uint, error) {
+	if names != nil {
+		if namedInt, ok := names[strings.ToLower(expr)]; ok {
+			return namedInt, nil
+		}
+	}
+	return mustParseInt(expr)
+}
+
+func mustParseInt(expr string) (uint, error) {
+	num, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse int from %q: %s", expr, err)
+	}
+	if num < 0 {
+		return 0, fmt.Errorf("negative number (%d) not allowed: %q", num, expr)
+	}
+	return uint(num), nil
+}
+
+// getBits sets all bits in [min, max], modulo the given step size.
+func getBits(min, max, step uint) uint64 {
+	var bits uint64
+
+	if step == 1 {
+		return ^(math.MaxUint64 << (max + 1)) & (math.MaxUint64 << min)
+	}
+
+	for i := min; i <= max; i += step {
+		bits |= 1 << i
+	}
+	return bits
+}
+
+// maskOverflow clears any bits set above the field's maximum value, except
+// the 'star' marker bit.
+func maskOverflow(bits uint64, r bounds) uint64 {
+	maxBit := uint64(1) << (r.max + 1)
+	return bits & (maxBit - 1 | starBit)
+}
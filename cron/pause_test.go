@@ -0,0 +1,62 @@
+package cron
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Pause an entry, expect it does not run, then resume it and expect it does.
+func TestPauseAndResume(t *testing.T) {
+	var calls int64
+
+	cron := newWithSeconds()
+	id, _ := cron.AddFunc("* * * * * ?", func() { atomic.AddInt64(&calls, 1) })
+	cron.Start()
+	defer cron.Stop()
+
+	cron.Pause(id)
+	select {
+	case <-time.After(OneSecond):
+	}
+	if n := atomic.LoadInt64(&calls); n != 0 {
+		t.Fatalf("expected paused entry not to run, ran %d times", n)
+	}
+
+	cron.Resume(id)
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		for atomic.LoadInt64(&calls) == 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		wg.Done()
+	}()
+
+	select {
+	case <-time.After(OneSecond):
+		t.Fatal("expected job to run after Resume")
+	case <-wait(wg):
+	}
+}
+
+// Entries added with WithPaused(true) should not run until resumed.
+func TestWithPausedOption(t *testing.T) {
+	var calls int64
+
+	cron := newWithSeconds()
+	id, _ := cron.AddFunc("* * * * * ?", func() { atomic.AddInt64(&calls, 1) }, WithPaused(true))
+	cron.Start()
+	defer cron.Stop()
+
+	<-time.After(OneSecond)
+	if n := atomic.LoadInt64(&calls); n != 0 {
+		t.Fatalf("expected entry added paused not to run, ran %d times", n)
+	}
+
+	entry := cron.Entry(id)
+	if !entry.Paused() {
+		t.Error("expected entry snapshot to report paused")
+	}
+}
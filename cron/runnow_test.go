@@ -0,0 +1,86 @@
+package cron
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// RunNow should trigger the job immediately, without waiting for the
+// schedule to fire, and without disturbing that schedule.
+func TestRunNow(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+
+	cron := newWithSeconds()
+	id, _ := cron.AddFunc("0 0 0 1 1 ?", func() { wg.Done() }) // next Jan 1st, effectively never
+	cron.Start()
+	defer cron.Stop()
+	next := cron.Entry(id).Next
+
+	if err := cron.RunNow(id); err != nil {
+		t.Fatalf("unexpected error from RunNow: %v", err)
+	}
+
+	select {
+	case <-time.After(OneSecond):
+		t.Fatal("expected RunNow to trigger the job immediately")
+	case <-wait(wg):
+	}
+
+	// RunNow must not disturb the entry's regular schedule.
+	if entry := cron.Entry(id); !entry.Prev.IsZero() || !entry.Next.Equal(next) {
+		t.Error("expected RunNow not to advance the entry's Prev/Next")
+	}
+}
+
+// RunNow on an unknown entry ID should report an error.
+func TestRunNowUnknownEntry(t *testing.T) {
+	cron := newWithSeconds()
+	cron.Start()
+	defer cron.Stop()
+
+	if err := cron.RunNow(EntryID(9999)); err == nil {
+		t.Fatal("expected an error for an unknown entry ID")
+	}
+}
+
+// RunNow should also work before the scheduler has been started.
+func TestRunNowBeforeStart(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+
+	cron := newWithSeconds()
+	id, _ := cron.AddFunc("0 0 0 1 1 ?", func() { wg.Done() })
+
+	if err := cron.RunNow(id); err != nil {
+		t.Fatalf("unexpected error from RunNow: %v", err)
+	}
+
+	select {
+	case <-time.After(OneSecond):
+		t.Fatal("expected RunNow to trigger the job immediately")
+	case <-wait(wg):
+	}
+}
+
+// RunNow before Start must not race with a concurrent AddFunc: both read
+// and write c.entries, and RunNow must hold runningMu for its entire body
+// (like Remove/Pause/Resume) rather than dropping it before calling
+// triggerEntry.
+func TestRunNowBeforeStartRacesWithAddFunc(t *testing.T) {
+	cron := newWithSeconds()
+	id, _ := cron.AddFunc("0 0 0 1 1 ?", func() {})
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		cron.AddFunc("0 0 0 1 1 ?", func() {})
+	}()
+	go func() {
+		defer wg.Done()
+		cron.RunNow(id)
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,63 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// StopGracefully should wait for an in-flight job to finish before
+// returning.
+func TestStopGracefullyWaitsForRunningJob(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan struct{})
+
+	cron := newWithSeconds()
+	cron.AddFunc("* * * * * ?", func() {
+		close(started)
+		<-release
+		close(finished)
+	})
+	cron.Start()
+
+	<-started
+
+	go func() {
+		close(release)
+	}()
+
+	if err := cron.StopGracefully(context.Background()); err != nil {
+		t.Fatalf("expected StopGracefully to succeed, got %v", err)
+	}
+
+	select {
+	case <-finished:
+	default:
+		t.Fatal("expected job to have finished before StopGracefully returned")
+	}
+}
+
+// StopGracefully should return the context's error if it is cancelled
+// before the running job finishes.
+func TestStopGracefullyRespectsContext(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	cron := newWithSeconds()
+	cron.AddFunc("* * * * * ?", func() {
+		close(started)
+		<-release
+	})
+	cron.Start()
+	defer close(release)
+
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := cron.StopGracefully(ctx); err == nil {
+		t.Fatal("expected StopGracefully to time out while job is still running")
+	}
+}
@@ -0,0 +1,85 @@
+// Package logging provides a minimal structured-logging abstraction used
+// across the cron and timer packages, so callers can plug in their own
+// logger without pulling in a third-party logging dependency.
+package logging
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+// Logger is the interface used by this library to log internal events. It is
+// intentionally kept close to the semantics of github.com/go-logr/logr:
+// Info logs routine events, Error logs unexpected failures.
+type Logger interface {
+	// Info logs routine events, giving a human readable message and
+	// key/value pairs of structured data.
+	Info(msg string, keysAndValues ...interface{})
+	// Error logs an error condition, giving a human readable message, the
+	// error, and key/value pairs of structured data.
+	Error(err error, msg string, keysAndValues ...interface{})
+}
+
+// DefaultLogger is used if no logger is specified via WithLogger.
+var DefaultLogger Logger = PrintfLogger(log.New(os.Stdout, "cron: ", log.LstdFlags))
+
+// DiscardLogger can be used to disable logging output entirely.
+var DiscardLogger Logger = PrintfLogger(log.New(discard{}, "", 0))
+
+type discard struct{}
+
+func (discard) Write(p []byte) (int, error) { return len(p), nil }
+
+// PrintfLogger wraps a *log.Logger into a Logger that logs every message at
+// the same level, using printf formatting.
+func PrintfLogger(l interface {
+	Printf(string, ...interface{})
+}) Logger {
+	return printfLogger{l}
+}
+
+type printfLogger struct {
+	logger interface {
+		Printf(string, ...interface{})
+	}
+}
+
+func (pl printfLogger) Info(msg string, keysAndValues ...interface{}) {
+	keysAndValues = formatTimes(keysAndValues)
+	pl.logger.Printf(formatString(len(keysAndValues)), append([]interface{}{msg}, keysAndValues...)...)
+}
+
+func (pl printfLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	keysAndValues = formatTimes(keysAndValues)
+	keysAndValues = append(keysAndValues, "error", err)
+	pl.logger.Printf(formatString(len(keysAndValues)), append([]interface{}{msg}, keysAndValues...)...)
+}
+
+// formatString returns a logfmt-like format string for the number of
+// key/value pairs given.
+func formatString(numKeysAndValues int) string {
+	s := "%s"
+	if numKeysAndValues > 0 {
+		s += ", "
+	}
+	for i := 0; i < numKeysAndValues/2; i++ {
+		if i > 0 {
+			s += ", "
+		}
+		s += "%v=%v"
+	}
+	return s
+}
+
+// formatTimes formats any time.Time values as RFC3339.
+func formatTimes(keysAndValues []interface{}) []interface{} {
+	formatted := make([]interface{}, len(keysAndValues))
+	for i, value := range keysAndValues {
+		if t, ok := value.(time.Time); ok {
+			value = t.Format(time.RFC3339)
+		}
+		formatted[i] = value
+	}
+	return formatted
+}
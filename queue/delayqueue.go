@@ -0,0 +1,167 @@
+// Package queue implements a delay queue modeled on java.util.concurrent.DelayQueue,
+// as used by Kafka's purgatory/timing-wheel design: elements only become
+// available once their delay has elapsed, and Pop blocks until the
+// earliest-expiring element is ready or the caller's context is cancelled.
+package queue
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// Delayed is implemented by anything that can be placed on a DelayQueue.
+type Delayed interface {
+	// GetDelay returns the remaining delay associated with this element.
+	GetDelay() time.Duration
+}
+
+// Clock abstracts the passage of time used by DelayQueue.Pop to wait out an
+// element's remaining delay, so that wait can be driven deterministically
+// in tests instead of depending on a real *time.Timer.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a Timer that fires once d has elapsed.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the subset of *time.Timer that DelayQueue relies on.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// realClock is the default Clock, backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                { return time.Now() }
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+type realTimer struct{ t *time.Timer }
+
+func (r realTimer) C() <-chan time.Time { return r.t.C }
+func (r realTimer) Stop() bool          { return r.t.Stop() }
+
+type item struct {
+	value Delayed
+	index int
+}
+
+type priorityQueue []*item
+
+func (pq priorityQueue) Len() int { return len(pq) }
+
+func (pq priorityQueue) Less(i, j int) bool {
+	return pq[i].value.GetDelay() < pq[j].value.GetDelay()
+}
+
+func (pq priorityQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].index = i
+	pq[j].index = j
+}
+
+func (pq *priorityQueue) Push(x interface{}) {
+	it := x.(*item)
+	it.index = len(*pq)
+	*pq = append(*pq, it)
+}
+
+func (pq *priorityQueue) Pop() interface{} {
+	old := *pq
+	n := len(old)
+	it := old[n-1]
+	old[n-1] = nil
+	it.index = -1
+	*pq = old[:n-1]
+	return it
+}
+
+// DelayQueue is a goroutine-safe, unbounded queue that only yields elements
+// once their delay has elapsed.
+type DelayQueue struct {
+	mu sync.Mutex
+	pq priorityQueue
+	// available is signalled whenever the head of the queue changes, so a
+	// blocked Pop can re-check whether its wait should be shortened.
+	available chan struct{}
+	clock     Clock
+}
+
+// NewDelay creates an empty DelayQueue backed by the real clock.
+func NewDelay() *DelayQueue {
+	return NewDelayWithClock(realClock{})
+}
+
+// NewDelayWithClock is like NewDelay, but lets the caller inject the Clock
+// Pop waits against - so an element's delay can be driven past by advancing
+// a MockClock, instead of Pop always waiting out real wall-clock time.
+func NewDelayWithClock(clock Clock) *DelayQueue {
+	return &DelayQueue{
+		available: make(chan struct{}, 1),
+		clock:     clock,
+	}
+}
+
+// Offer inserts an element into the queue.
+func (q *DelayQueue) Offer(d Delayed) {
+	q.mu.Lock()
+	heap.Push(&q.pq, &item{value: d})
+	q.mu.Unlock()
+	q.signal()
+}
+
+func (q *DelayQueue) signal() {
+	select {
+	case q.available <- struct{}{}:
+	default:
+	}
+}
+
+// Pop removes and returns the element at the head of the queue, waiting
+// until it expires. It returns nil if ctx is cancelled before an element
+// becomes available.
+func (q *DelayQueue) Pop(ctx context.Context) interface{} {
+	for {
+		q.mu.Lock()
+		if len(q.pq) == 0 {
+			q.mu.Unlock()
+			select {
+			case <-q.available:
+				continue
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		head := q.pq[0]
+		delay := head.value.GetDelay()
+		if delay <= 0 {
+			heap.Pop(&q.pq)
+			q.mu.Unlock()
+			return head.value
+		}
+		q.mu.Unlock()
+
+		timer := q.clock.NewTimer(delay)
+		select {
+		case <-timer.C():
+			continue
+		case <-q.available:
+			timer.Stop()
+			continue
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		}
+	}
+}
+
+// Size returns the number of elements currently in the queue.
+func (q *DelayQueue) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pq)
+}
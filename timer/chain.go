@@ -0,0 +1,78 @@
+package timer
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/GuoCeng/time-wheel/logging"
+)
+
+// JobWrapper decorates the given Runnable with some behavior, mirroring the
+// cron package's JobWrapper but for timer tasks.
+type JobWrapper func(Runnable) Runnable
+
+// Chain is a sequence of JobWrappers that decorates a Runnable with
+// behaviors such as panic recovery or overlap handling.
+type Chain struct {
+	wrappers []JobWrapper
+}
+
+// NewChain returns a Chain consisting of the given JobWrappers.
+func NewChain(c ...JobWrapper) Chain {
+	return Chain{c}
+}
+
+// Then decorates r with every JobWrapper in the Chain, in the order they
+// were added: Chain(f1, f2).Then(r) == f1(f2(r)).
+func (c Chain) Then(r Runnable) Runnable {
+	for i := range c.wrappers {
+		r = c.wrappers[len(c.wrappers)-i-1](r)
+	}
+	return r
+}
+
+type runnableFunc func()
+
+func (f runnableFunc) Run() { f() }
+
+// Recover wraps r so a panic during Run is logged with logger rather than
+// crashing the timer's goroutine.
+func Recover(logger logging.Logger) JobWrapper {
+	return func(r Runnable) Runnable {
+		return runnableFunc(func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					const size = 64 << 10
+					buf := make([]byte, size)
+					buf = buf[:runtime.Stack(buf, false)]
+					err, ok := rec.(error)
+					if !ok {
+						err = fmt.Errorf("%v", rec)
+					}
+					logger.Error(err, "panic", "stack", "...\n"+string(buf))
+				}
+			}()
+			r.Run()
+		})
+	}
+}
+
+// SkipIfStillRunning skips a Run of the returned Runnable if a previous Run
+// of that same Runnable is still in flight, logging the skip to logger at
+// Info level. SystemTimer applies its Chain anew on every dispatch (see
+// runTask), so when installed through WithChain, separate dispatches of a
+// task do not share this state and none of them is skipped.
+func SkipIfStillRunning(logger logging.Logger) JobWrapper {
+	return func(r Runnable) Runnable {
+		var running int32
+		return runnableFunc(func() {
+			if !atomic.CompareAndSwapInt32(&running, 0, 1) {
+				logger.Info("skip")
+				return
+			}
+			defer atomic.StoreInt32(&running, 0)
+			r.Run()
+		})
+	}
+}
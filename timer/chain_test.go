@@ -0,0 +1,79 @@
+package timer
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GuoCeng/time-wheel/logging"
+)
+
+type syncWriter struct {
+	wr bytes.Buffer
+	m  sync.Mutex
+}
+
+func (sw *syncWriter) Write(data []byte) (n int, err error) {
+	sw.m.Lock()
+	n, err = sw.wr.Write(data)
+	sw.m.Unlock()
+	return
+}
+
+func (sw *syncWriter) String() string {
+	sw.m.Lock()
+	defer sw.m.Unlock()
+	return sw.wr.String()
+}
+
+func newBufLogger(sw *syncWriter) logging.Logger {
+	return logging.PrintfLogger(log.New(sw, "", log.LstdFlags))
+}
+
+// A task that panics when dispatched via the "already expired" path (Add
+// with a non-positive delay runs it immediately, without ever entering the
+// wheel) must be recovered by the configured Chain and logged.
+func TestChainRecoversPanicOnAlreadyExpiredPath(t *testing.T) {
+	var buf syncWriter
+	st := NewSystemTimer(10*time.Millisecond, 4, WithChain(Recover(newBufLogger(&buf))))
+
+	st.Add(NewTask(runnableFunc(func() { panic("YOLO") }), 0))
+
+	deadline := time.Now().Add(testTimeout)
+	for !strings.Contains(buf.String(), "YOLO") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !strings.Contains(buf.String(), "YOLO") {
+		t.Fatal("expected the panic to be recovered and logged")
+	}
+}
+
+// A task that panics when dispatched via the AdvanceClock flush path (it
+// was sitting in a wheel bucket, and migrates down into execution once the
+// clock advances past its expiration) must also be recovered and logged.
+func TestChainRecoversPanicOnAdvanceClockPath(t *testing.T) {
+	var buf syncWriter
+	clock := NewMockClock()
+	st := NewSystemTimerWithClock(10*time.Millisecond, 4, clock, WithChain(Recover(newBufLogger(&buf))))
+
+	st.Add(NewTask(runnableFunc(func() { panic("YOLO") }), 25*time.Millisecond))
+	clock.Add(30 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	if !st.AdvanceClock(ctx) {
+		t.Fatal("expected AdvanceClock to find an expired bucket")
+	}
+
+	deadline := time.Now().Add(testTimeout)
+	for !strings.Contains(buf.String(), "YOLO") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !strings.Contains(buf.String(), "YOLO") {
+		t.Fatal("expected the panic to be recovered and logged")
+	}
+}
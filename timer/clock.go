@@ -0,0 +1,37 @@
+package timer
+
+import (
+	"time"
+
+	"github.com/GuoCeng/time-wheel/queue"
+)
+
+// clockTimer is the subset of *time.Timer this package relies on, abstracted
+// so MockClock can drive it without a real timer running in the background.
+// It is an alias for queue.Timer so a Clock also satisfies queue.Clock
+// without conversion.
+type clockTimer = queue.Timer
+
+// Clock abstracts the passage of time used by SystemTimer, so it can be
+// driven deterministically in tests instead of relying on wall-clock time.
+// It embeds queue.Clock so the same Clock also drives the real-time wait
+// inside the queue.DelayQueue that backs AdvanceClock - advancing a
+// MockClock wakes a blocked AdvanceClock immediately, rather than only
+// affecting already-expired-by-the-time-you-poll scenarios.
+type Clock interface {
+	queue.Clock
+	// Sleep blocks for d.
+	Sleep(d time.Duration)
+}
+
+// realClock is the default Clock, backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                      { return time.Now() }
+func (realClock) NewTimer(d time.Duration) clockTimer { return realTimer{time.NewTimer(d)} }
+func (realClock) Sleep(d time.Duration)               { time.Sleep(d) }
+
+type realTimer struct{ t *time.Timer }
+
+func (r realTimer) C() <-chan time.Time { return r.t.C }
+func (r realTimer) Stop() bool          { return r.t.Stop() }
@@ -0,0 +1,79 @@
+package timer
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// A task added to a SystemTimer driven by a MockClock must not run until
+// the clock is advanced past its expiration, and then runs exactly once -
+// all without depending on real elapsed wall-clock time.
+func TestSystemTimerWithMockClockDrivesAddAndAdvance(t *testing.T) {
+	clock := NewMockClock()
+	st := NewSystemTimerWithClock(10*time.Millisecond, 4, clock)
+
+	ran := make(chan struct{}, 1)
+	st.Add(NewTask(runnableFunc(func() { ran <- struct{}{} }), 25*time.Millisecond))
+
+	if got, want := st.Size(), int64(1); got != want {
+		t.Fatalf("expected Size() == %d, got %d", want, got)
+	}
+
+	select {
+	case <-ran:
+		t.Fatal("expected the task not to run before the mock clock advances")
+	default:
+	}
+
+	clock.Add(30 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	if !st.AdvanceClock(ctx) {
+		t.Fatal("expected AdvanceClock to find an expired bucket")
+	}
+
+	select {
+	case <-ran:
+	case <-time.After(testTimeout):
+		t.Fatal("expected the task to run after advancing the mock clock")
+	}
+
+	if got, want := st.Size(), int64(0); got != want {
+		t.Fatalf("expected Size() == %d once the task has run, got %d", want, got)
+	}
+}
+
+// AdvanceClock must wake as soon as the mock clock is advanced past a
+// task's expiration, even if it was already blocked waiting for that
+// expiration when the clock advanced - not just when the clock happens to
+// already be past the expiration before AdvanceClock is called.
+func TestSystemTimerWithMockClockWakesAdvanceClockAlreadyBlocked(t *testing.T) {
+	clock := NewMockClock()
+	st := NewSystemTimerWithClock(10*time.Millisecond, 4, clock)
+
+	st.Add(NewTask(runnableFunc(func() {}), 2*time.Hour))
+
+	blocked := make(chan struct{})
+	result := make(chan bool, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	go func() {
+		close(blocked)
+		result <- st.AdvanceClock(ctx)
+	}()
+
+	<-blocked
+	time.Sleep(20 * time.Millisecond) // give AdvanceClock a chance to actually block in Pop
+	clock.Add(2 * time.Hour)
+
+	select {
+	case ok := <-result:
+		if !ok {
+			t.Fatal("expected AdvanceClock to find the now-expired bucket")
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("expected advancing the mock clock to wake AdvanceClock immediately, not wait out the real ctx deadline")
+	}
+}
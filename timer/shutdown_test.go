@@ -0,0 +1,61 @@
+package timer
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// ShutdownContext should wait for an in-flight task to finish before
+// returning.
+func TestShutdownContextWaitsForRunningTask(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan struct{})
+
+	st := NewSystemTimer(10*time.Millisecond, 4)
+	st.Add(NewTask(runnableFunc(func() {
+		close(started)
+		<-release
+		close(finished)
+	}), 0))
+
+	<-started
+
+	go func() {
+		close(release)
+	}()
+
+	if err := st.ShutdownContext(context.Background()); err != nil {
+		t.Fatalf("expected ShutdownContext to succeed, got %v", err)
+	}
+
+	select {
+	case <-finished:
+	default:
+		t.Fatal("expected task to have finished before ShutdownContext returned")
+	}
+}
+
+// ShutdownContext should return the context's error if it is cancelled
+// before the running task finishes.
+func TestShutdownContextRespectsContext(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	st := NewSystemTimer(10*time.Millisecond, 4)
+	st.Add(NewTask(runnableFunc(func() {
+		close(started)
+		<-release
+	}), 0))
+	defer close(release)
+
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := st.ShutdownContext(ctx); err == nil {
+		t.Fatal("expected ShutdownContext to time out while the task is still running")
+	}
+}
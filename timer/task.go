@@ -0,0 +1,98 @@
+package timer
+
+import (
+	"sync"
+	"time"
+)
+
+// Runnable is anything that can be executed when a Task's delay elapses.
+type Runnable interface {
+	Run()
+}
+
+// Task is a unit of deferred work submitted to a Timer. It carries the delay
+// it was scheduled with and the Runnable to execute once that delay elapses.
+type Task struct {
+	mu      sync.Mutex
+	delayMs time.Duration
+
+	runnable Runnable
+
+	// entry points at the TaskEntry currently representing this task inside
+	// a timing wheel bucket. A TaskEntry is considered cancelled once the
+	// task it belongs to no longer points back at it (e.g. because the task
+	// was rescheduled into a new entry, or explicitly cancelled).
+	entry *TaskEntry
+}
+
+// NewTask creates a Task that will run r after delayMs elapses.
+func NewTask(r Runnable, delayMs time.Duration) *Task {
+	return &Task{
+		delayMs:  delayMs,
+		runnable: r,
+	}
+}
+
+func (t *Task) run() {
+	t.runnable.Run()
+}
+
+// Cancel detaches the task from whatever TaskEntry currently represents it,
+// so the next time that entry is encountered it is treated as cancelled.
+func (t *Task) Cancel() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.entry != nil {
+		t.entry.remove()
+	}
+	t.entry = nil
+}
+
+// setTaskEntry records e as the TaskEntry currently representing this task,
+// detaching the previous one (if any) from its bucket.
+func (t *Task) setTaskEntry(e *TaskEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.entry != nil && t.entry != e {
+		t.entry.remove()
+	}
+	t.entry = e
+}
+
+// TaskEntry is a node in a TaskList's doubly-linked list, pairing a Task
+// with the absolute expiration (a Duration since the Unix epoch, as
+// SystemTimer.Add computes it from Clock.Now().UnixNano()) it was
+// scheduled for.
+type TaskEntry struct {
+	task         *Task
+	expirationMs time.Duration
+
+	list       *TaskList
+	prev, next *TaskEntry
+}
+
+// NewTaskEntry wraps task in an entry expiring at expirationMs and links the
+// task back to this entry so cancellation/rescheduling can be detected.
+func NewTaskEntry(task *Task, expirationMs time.Duration) *TaskEntry {
+	e := &TaskEntry{
+		task:         task,
+		expirationMs: expirationMs,
+	}
+	if task != nil {
+		task.setTaskEntry(e)
+	}
+	return e
+}
+
+// cancelled reports whether this entry has been superseded or explicitly
+// cancelled: its task no longer points back at it.
+func (e *TaskEntry) cancelled() bool {
+	return e.task == nil || e.task.entry != e
+}
+
+// remove detaches this entry from whichever TaskList currently holds it.
+func (e *TaskEntry) remove() {
+	if e.list != nil {
+		e.list.remove(e)
+	}
+}
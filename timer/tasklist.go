@@ -0,0 +1,125 @@
+package timer
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TaskList is a bucket of a TimingWheel: a doubly-linked list of TaskEntry
+// values that all share (approximately) the same expiration. It implements
+// queue.Delayed so buckets can be registered directly with a DelayQueue.
+type TaskList struct {
+	mu sync.Mutex
+	// expirationMs is the absolute time (ns since the Unix epoch) at
+	// which this bucket's tasks are due, or -1 if the bucket is empty and
+	// not currently registered with the delay queue. Stored atomically so
+	// GetDelay can be read without holding mu.
+	expirationMs int64
+
+	root *TaskEntry // sentinel; root.next/root.prev form the ring
+
+	taskCounter *int64
+	clock       Clock
+}
+
+// NewTaskList creates an empty bucket. taskCounter is shared across every
+// bucket/level of a timer so Size() reflects the true total pending count,
+// and clock is used by GetDelay to compute the remaining time until
+// expiration.
+func NewTaskList(taskCounter *int64, clock Clock) *TaskList {
+	root := &TaskEntry{}
+	root.next = root
+	root.prev = root
+	return &TaskList{
+		expirationMs: -1,
+		root:         root,
+		taskCounter:  taskCounter,
+		clock:        clock,
+	}
+}
+
+// setExpiration updates the bucket's expiration, returning true if it
+// changed (in which case the caller should (re-)register the bucket with
+// the delay queue).
+func (l *TaskList) setExpiration(expirationMs time.Duration) bool {
+	return atomic.SwapInt64(&l.expirationMs, int64(expirationMs)) != int64(expirationMs)
+}
+
+// GetExpiration returns the bucket's current absolute expiration.
+func (l *TaskList) GetExpiration() time.Duration {
+	return time.Duration(atomic.LoadInt64(&l.expirationMs))
+}
+
+// GetDelay implements queue.Delayed by returning the time remaining until
+// this bucket's expiration.
+func (l *TaskList) GetDelay() time.Duration {
+	delay := l.GetExpiration() - time.Duration(l.clock.Now().UnixNano())
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// add appends entry to this bucket.
+func (l *TaskList) add(entry *TaskEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry.remove()
+
+	entry.next = l.root
+	entry.prev = l.root.prev
+	entry.prev.next = entry
+	entry.next.prev = entry
+	entry.list = l
+
+	if l.taskCounter != nil {
+		atomic.AddInt64(l.taskCounter, 1)
+	}
+}
+
+// remove unlinks entry from this bucket, if it is still in it.
+func (l *TaskList) remove(entry *TaskEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removeLocked(entry)
+}
+
+func (l *TaskList) removeLocked(entry *TaskEntry) {
+	if entry.list != l {
+		return
+	}
+	entry.next.prev = entry.prev
+	entry.prev.next = entry.next
+	entry.next = nil
+	entry.prev = nil
+	entry.list = nil
+
+	if l.taskCounter != nil {
+		atomic.AddInt64(l.taskCounter, -1)
+	}
+}
+
+// flush removes every entry from the bucket, invoking f for each one that
+// has not been cancelled in the meantime, and resets the bucket's
+// expiration so it can be reused.
+func (l *TaskList) flush(f func(*TaskEntry)) {
+	l.mu.Lock()
+	entry := l.root.next
+	l.mu.Unlock()
+
+	for entry != l.root {
+		l.mu.Lock()
+		next := entry.next
+		l.removeLocked(entry)
+		l.mu.Unlock()
+
+		if !entry.cancelled() {
+			f(entry)
+		}
+		entry = next
+	}
+
+	atomic.StoreInt64(&l.expirationMs, -1)
+}
@@ -3,6 +3,7 @@ package timer
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/GuoCeng/time-wheel/queue"
@@ -32,24 +33,51 @@ type Timer interface {
 	Size() int64
 
 	/**
-	 * Shutdown the timer service, leaving pending tasks unexecuted
+	 * Shutdown the timer service, leaving pending tasks unexecuted. Blocks
+	 * until any tasks already dispatched for execution have finished.
 	 */
 	Shutdown()
 }
 
-func NewSystemTimer(tickMs time.Duration, wheelSize int) *SystemTimer {
-	startMs := time.Duration(time.Now().Nanosecond())
-	q := queue.NewDelay()
-	return &SystemTimer{
-		tickMs:      tickMs,
-		wheelSize:   wheelSize,
-		startMs:     startMs,
-		delayQueue:  q,
-		taskCounter: 0,
-		timingWheel: NewTimingWheel(tickMs, wheelSize, startMs, 0, q, 0),
+// Option configures a SystemTimer at construction time.
+type Option func(*SystemTimer)
+
+// WithChain wraps every task this SystemTimer executes with the given set
+// of JobWrappers, applied in the order given (WithChain(f1, f2) runs as
+// f1(f2(task))).
+func WithChain(wrappers ...JobWrapper) Option {
+	return func(t *SystemTimer) {
+		t.chain = NewChain(wrappers...)
 	}
 }
 
+func NewSystemTimer(tickMs time.Duration, wheelSize int, opts ...Option) *SystemTimer {
+	return NewSystemTimerWithClock(tickMs, wheelSize, realClock{}, opts...)
+}
+
+// NewSystemTimerWithClock is like NewSystemTimer, but lets the caller
+// inject the Clock used to read the current time, so tests can drive the
+// wheel deterministically instead of depending on wall-clock time.
+func NewSystemTimerWithClock(tickMs time.Duration, wheelSize int, clock Clock, opts ...Option) *SystemTimer {
+	startMs := time.Duration(clock.Now().UnixNano())
+	q := queue.NewDelayWithClock(clock)
+	t := &SystemTimer{
+		tickMs:     tickMs,
+		wheelSize:  wheelSize,
+		startMs:    startMs,
+		delayQueue: q,
+		clock:      clock,
+	}
+	// taskCounter is shared (by pointer) with every level of the hierarchy
+	// created below, so Size() reflects the true total pending count even
+	// once tasks start overflowing into coarser wheels.
+	t.timingWheel = NewTimingWheel(tickMs, wheelSize, startMs, &t.taskCounter, q, clock)
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
 type SystemTimer struct {
 	mu          sync.RWMutex
 	tickMs      time.Duration
@@ -57,26 +85,42 @@ type SystemTimer struct {
 	startMs     time.Duration
 	delayQueue  *queue.DelayQueue
 	taskCounter int64
+	clock       Clock
 	timingWheel *TimingWheel
+	chain       Chain
+
+	// jobWaiter tracks tasks that have been dispatched for execution, so
+	// Shutdown can block until they've all returned.
+	jobWaiter sync.WaitGroup
 }
 
 func (t *SystemTimer) Add(task *Task) {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
-	t.addTimerTaskEntry(NewTaskEntry(task, task.delayMs+time.Duration(time.Now().Nanosecond())))
+	t.addTimerTaskEntry(NewTaskEntry(task, task.delayMs+time.Duration(t.clock.Now().UnixNano())))
 }
 
 func (t *SystemTimer) addTimerTaskEntry(taskEntry *TaskEntry) {
 	if !t.timingWheel.add(taskEntry) {
 		// Already expired or cancelled
 		if !taskEntry.cancelled() {
-			go func() {
-				taskEntry.task.run()
-			}()
+			t.runTask(taskEntry.task)
 		}
 	}
 }
 
+// runTask dispatches task.run() - wrapped with this timer's configured
+// Chain - in its own goroutine, tracked by jobWaiter so Shutdown can wait
+// for it to complete.
+func (t *SystemTimer) runTask(task *Task) {
+	wrapped := t.chain.Then(runnableFunc(task.run))
+	t.jobWaiter.Add(1)
+	go func() {
+		defer t.jobWaiter.Done()
+		wrapped.Run()
+	}()
+}
+
 // Advances the clock if there is an expired bucket. If there isn't any expired bucket when called,
 // waits up to timeoutMs before giving up.
 func (t *SystemTimer) AdvanceClock(ctx context.Context) bool {
@@ -86,12 +130,23 @@ func (t *SystemTimer) AdvanceClock(ctx context.Context) bool {
 			t.mu.Lock()
 			defer t.mu.Unlock()
 			for v != nil {
-				t.timingWheel.advanceClock(v.GetDelay())
+				t.timingWheel.advanceClock(v.GetExpiration())
 				v.flush(func(e *TaskEntry) {
-					go func() {
-						e.task.run()
-					}()
+					// Re-insert rather than run directly: a bucket from an
+					// overflow wheel hasn't actually reached its deadline
+					// yet, it's only now close enough to fit a finer
+					// wheel. addTimerTaskEntry runs it immediately only if
+					// it's truly expired, and otherwise re-buckets it on the
+					// finest wheel its remaining delay fits.
+					t.addTimerTaskEntry(e)
 				})
+				if t.delayQueue.Size() == 0 {
+					// Nothing else is due right now - stop draining instead
+					// of blocking on Pop until ctx expires just to confirm
+					// that.
+					v = nil
+					continue
+				}
 				x := t.delayQueue.Pop(ctx)
 				if x != nil {
 					v = x.(*TaskList)
@@ -108,11 +163,30 @@ func (t *SystemTimer) AdvanceClock(ctx context.Context) bool {
 }
 
 func (t *SystemTimer) Size() int64 {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
-	return t.taskCounter
+	return atomic.LoadInt64(&t.taskCounter)
 }
 
+// Shutdown leaves any still-pending tasks unexecuted, but blocks until every
+// task already dispatched for execution has finished running.
 func (t *SystemTimer) Shutdown() {
+	t.jobWaiter.Wait()
+}
 
+// ShutdownContext is like Shutdown, but returns once every dispatched task
+// has finished or ctx is cancelled first, whichever happens first. It is
+// the timer-level equivalent of Cron.StopGracefully, for callers that need
+// an escape hatch from a task that never returns.
+func (t *SystemTimer) ShutdownContext(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		t.jobWaiter.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
\ No newline at end of file
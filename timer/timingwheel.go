@@ -0,0 +1,120 @@
+package timer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/GuoCeng/time-wheel/queue"
+)
+
+// TimingWheel divides time into wheelSize buckets of tickMs each, covering
+// an interval of tickMs*wheelSize, and is modeled after Kafka's purgatory
+// timing wheel: buckets are registered with a DelayQueue whenever their
+// expiration changes, so a single blocking Pop is enough to learn when the
+// next bucket is due.
+//
+// A task whose expiration doesn't fit within this wheel's interval is
+// deferred to an overflowWheel - a coarser-grained wheel with
+// tickMs == this wheel's interval - which is allocated lazily the first
+// time it's needed. As time advances and an overflow bucket's tasks come
+// back due, SystemTimer re-inserts them (see addTimerTaskEntry), letting
+// them migrate down into finer-grained wheels until they finally expire.
+// This bounds memory use for delays ranging from milliseconds to days.
+type TimingWheel struct {
+	tickMs    time.Duration
+	wheelSize int
+	startMs   time.Duration
+
+	// taskCounter is shared by every level of the hierarchy (and every
+	// bucket within each level), so Size() reflects the true total.
+	taskCounter *int64
+
+	queue *queue.DelayQueue
+	clock Clock
+
+	interval    time.Duration
+	buckets     []*TaskList
+	currentTime time.Duration
+
+	overflowMu    sync.Mutex
+	overflowWheel *TimingWheel
+}
+
+// NewTimingWheel creates a wheel level. taskCounter is shared across every
+// level of the hierarchy, and clock is used by buckets to compute their
+// remaining delay.
+func NewTimingWheel(tickMs time.Duration, wheelSize int, startMs time.Duration, taskCounter *int64, q *queue.DelayQueue, clock Clock) *TimingWheel {
+	buckets := make([]*TaskList, wheelSize)
+	for i := range buckets {
+		buckets[i] = NewTaskList(taskCounter, clock)
+	}
+
+	return &TimingWheel{
+		tickMs:      tickMs,
+		wheelSize:   wheelSize,
+		startMs:     startMs,
+		taskCounter: taskCounter,
+		queue:       q,
+		clock:       clock,
+		interval:    tickMs * time.Duration(wheelSize),
+		buckets:     buckets,
+		currentTime: startMs - (startMs % tickMs),
+	}
+}
+
+// overflow returns this wheel's overflow wheel, lazily creating it (with
+// tickMs equal to this wheel's interval) the first time it's needed.
+func (w *TimingWheel) overflow() *TimingWheel {
+	w.overflowMu.Lock()
+	defer w.overflowMu.Unlock()
+	if w.overflowWheel == nil {
+		w.overflowWheel = NewTimingWheel(w.interval, w.wheelSize, w.currentTime, w.taskCounter, w.queue, w.clock)
+	}
+	return w.overflowWheel
+}
+
+// add inserts taskEntry into the appropriate bucket, returning false if the
+// entry has already expired (or was cancelled), in which case the caller is
+// responsible for running it immediately.
+func (w *TimingWheel) add(taskEntry *TaskEntry) bool {
+	if taskEntry.cancelled() {
+		return false
+	}
+
+	expiration := taskEntry.expirationMs
+
+	switch {
+	case expiration < w.currentTime+w.tickMs:
+		// Already expired.
+		return false
+	case expiration < w.currentTime+w.interval:
+		// Find the virtual bucket this expiration maps to.
+		virtualID := int64(expiration / w.tickMs)
+		bucket := w.buckets[virtualID%int64(w.wheelSize)]
+		bucket.add(taskEntry)
+
+		if bucket.setExpiration(time.Duration(virtualID) * w.tickMs) {
+			w.queue.Offer(bucket)
+		}
+		return true
+	default:
+		// Doesn't fit on this wheel: defer to the (coarser) overflow wheel.
+		return w.overflow().add(taskEntry)
+	}
+}
+
+// advanceClock moves this wheel's notion of the current time forward to
+// timeMs, if it is later than the current value, propagating the advance
+// to the overflow wheel (if any) so its buckets expire in turn.
+func (w *TimingWheel) advanceClock(timeMs time.Duration) {
+	if timeMs >= w.currentTime+w.tickMs {
+		w.currentTime = timeMs - (timeMs % w.tickMs)
+
+		w.overflowMu.Lock()
+		ow := w.overflowWheel
+		w.overflowMu.Unlock()
+		if ow != nil {
+			ow.advanceClock(w.currentTime)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package timer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GuoCeng/time-wheel/queue"
+)
+
+// testTimeout bounds how long these tests will wait on a DelayQueue.Pop
+// before giving up and failing, analogous to cron's OneSecond.
+const testTimeout = 1 * time.Second
+
+// tick drains the next expired bucket from q, advances w's notion of the
+// current time to match it, and re-inserts every entry the bucket held -
+// returning the ones that turned out to already be expired, mirroring what
+// SystemTimer.addTimerTaskEntry would dispatch immediately.
+func tick(t *testing.T, w *TimingWheel, q *queue.DelayQueue) []*TaskEntry {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	bucket, ok := q.Pop(ctx).(*TaskList)
+	if !ok {
+		t.Fatal("expected an expired bucket from the delay queue")
+	}
+
+	w.advanceClock(bucket.GetExpiration())
+
+	var expired []*TaskEntry
+	bucket.flush(func(e *TaskEntry) {
+		if !w.add(e) {
+			expired = append(expired, e)
+		}
+	})
+	return expired
+}
+
+// newTestWheel builds a base wheel driven by the real clock, returning the
+// current time (in the wheel's ns-since-epoch units) alongside it so tests
+// can compute expirationMs values relative to "now", the same way
+// SystemTimer.Add does.
+func newTestWheel(tickMs time.Duration, wheelSize int) (w *TimingWheel, q *queue.DelayQueue, counter *int64, now time.Duration) {
+	q = queue.NewDelay()
+	counter = new(int64)
+	now = time.Duration(realClock{}.Now().UnixNano())
+	w = NewTimingWheel(tickMs, wheelSize, now, counter, q, realClock{})
+	return w, q, counter, now
+}
+
+// A task whose delay exceeds the base wheel's interval should overflow into
+// a coarser wheel, then migrate back down one level at a time as the clock
+// advances, firing exactly once it's truly due.
+func TestTimingWheelMigratesOverflowTaskDown(t *testing.T) {
+	w, q, counter, now := newTestWheel(10*time.Millisecond, 4) // interval = 40ms
+
+	task := NewTask(runnableFunc(func() {}), 0)
+	entry := NewTaskEntry(task, now+55*time.Millisecond)
+
+	if !w.add(entry) {
+		t.Fatal("expected the entry to be accepted, not already expired")
+	}
+	if w.overflowWheel == nil {
+		t.Fatal("expected the overflow wheel to have been allocated")
+	}
+	if got := *counter; got != 1 {
+		t.Fatalf("expected taskCounter == 1, got %d", got)
+	}
+
+	var fired []*TaskEntry
+	for i := 0; i < 10 && len(fired) == 0; i++ {
+		fired = tick(t, w, q)
+	}
+
+	if len(fired) != 1 || fired[0].task != task {
+		t.Fatalf("expected the overflowed task to fire exactly once, got %v", fired)
+	}
+	if got := *counter; got != 0 {
+		t.Fatalf("expected taskCounter == 0 once the task has fired, got %d", got)
+	}
+}
+
+// A sufficiently long delay should overflow through three or more levels of
+// wheels before the task finally migrates down to expiration.
+func TestTimingWheelMultiLevelOverflow(t *testing.T) {
+	w, q, counter, now := newTestWheel(10*time.Millisecond, 4)
+	// level0 interval=40ms, level1 tickMs=40ms interval=160ms,
+	// level2 tickMs=160ms interval=640ms, level3 tickMs=640ms interval=2560ms.
+	// A 700ms delay doesn't fit on level0, level1 or level2, so it must
+	// overflow three levels deep before it can be placed.
+	task := NewTask(runnableFunc(func() {}), 0)
+	entry := NewTaskEntry(task, now+700*time.Millisecond)
+
+	if !w.add(entry) {
+		t.Fatal("expected the entry to be accepted, not already expired")
+	}
+
+	level1 := w.overflowWheel
+	if level1 == nil || level1.overflowWheel == nil || level1.overflowWheel.overflowWheel == nil {
+		t.Fatal("expected at least three levels of overflow wheels to be allocated")
+	}
+	if got := *counter; got != 1 {
+		t.Fatalf("expected taskCounter == 1, got %d", got)
+	}
+
+	var fired []*TaskEntry
+	for i := 0; i < 50 && len(fired) == 0; i++ {
+		fired = tick(t, w, q)
+	}
+
+	if len(fired) != 1 || fired[0].task != task {
+		t.Fatalf("expected the task to migrate down and fire exactly once, got %v", fired)
+	}
+	if got := *counter; got != 0 {
+		t.Fatalf("expected taskCounter == 0 once the task has fired, got %d", got)
+	}
+}
+
+// Cancelling a task while it sits in an overflow bucket must remove it from
+// the taskCounter immediately, and the bucket must skip it (without
+// running it or panicking) once that bucket is eventually flushed.
+func TestTimingWheelCancelInOverflowBucket(t *testing.T) {
+	w, q, counter, now := newTestWheel(10*time.Millisecond, 4)
+
+	ran := false
+	task := NewTask(runnableFunc(func() { ran = true }), 0)
+	entry := NewTaskEntry(task, now+500*time.Millisecond)
+
+	if !w.add(entry) {
+		t.Fatal("expected the entry to be accepted, not already expired")
+	}
+	if got := *counter; got != 1 {
+		t.Fatalf("expected taskCounter == 1 before cancelling, got %d", got)
+	}
+
+	task.Cancel()
+
+	if got := *counter; got != 0 {
+		t.Fatalf("expected taskCounter == 0 immediately after cancelling, got %d", got)
+	}
+
+	for i := 0; i < 50; i++ {
+		fired := tick(t, w, q)
+		if len(fired) != 0 {
+			t.Fatalf("expected a cancelled entry never to fire, got %v", fired)
+		}
+		if ran {
+			t.Fatal("expected a cancelled task's Runnable never to run")
+		}
+		if q.Size() == 0 {
+			break
+		}
+	}
+	if got := *counter; got != 0 {
+		t.Fatalf("expected taskCounter to remain 0, got %d", got)
+	}
+}
+
+// taskCounter must stay accurate as tasks land on, and later fire from,
+// different levels of the wheel hierarchy.
+func TestTimingWheelSizeAcrossLevels(t *testing.T) {
+	w, q, counter, now := newTestWheel(10*time.Millisecond, 4)
+
+	delays := []time.Duration{
+		-5 * time.Millisecond,  // already expired
+		25 * time.Millisecond,  // fits on the base wheel
+		100 * time.Millisecond, // overflows one level
+		700 * time.Millisecond, // overflows three levels
+	}
+
+	var entries []*TaskEntry
+	for _, d := range delays {
+		task := NewTask(runnableFunc(func() {}), 0)
+		entry := NewTaskEntry(task, now+d)
+		if !w.add(entry) {
+			// Already expired: this is the -5ms case, which never enters a
+			// bucket, so it shouldn't count towards taskCounter.
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if got, want := *counter, int64(len(entries)); got != want {
+		t.Fatalf("expected taskCounter == %d, got %d", want, got)
+	}
+
+	remaining := len(entries)
+	for i := 0; i < 100 && remaining > 0; i++ {
+		fired := tick(t, w, q)
+		remaining -= len(fired)
+		if got, want := *counter, int64(remaining); got != want {
+			t.Fatalf("expected taskCounter == %d after a tick, got %d", want, got)
+		}
+	}
+	if remaining != 0 {
+		t.Fatalf("expected every entry to have fired, %d left outstanding", remaining)
+	}
+}